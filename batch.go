@@ -0,0 +1,126 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultBatchConcurrency is the number of workers BatchGeocode uses when
+// no WithConcurrency option is given.
+const DefaultBatchConcurrency = 5
+
+type batchConfig struct {
+	concurrency int
+	qps         float64
+	progress    func(done, total int)
+}
+
+// BatchOption configures BatchGeocode.
+type BatchOption func(*batchConfig)
+
+// WithConcurrency sets the number of requests BatchGeocode issues in
+// parallel. It defaults to DefaultBatchConcurrency.
+func WithConcurrency(n int) BatchOption {
+	return func(c *batchConfig) { c.concurrency = n }
+}
+
+// WithRateLimit caps BatchGeocode at qps requests per second across all
+// workers, matching Google's per-second quotas. Zero (the default) means
+// unlimited.
+func WithRateLimit(qps float64) BatchOption {
+	return func(c *batchConfig) { c.qps = qps }
+}
+
+// WithProgress registers fn to be called as requests complete, reporting
+// how many of total have finished so far. fn is called from whichever
+// worker goroutine finishes a request, so it must be safe for concurrent
+// use and should not block.
+func WithProgress(fn func(done, total int)) BatchOption {
+	return func(c *batchConfig) { c.progress = fn }
+}
+
+// BatchGeocode geocodes requests concurrently over a bounded worker pool,
+// optionally throttled to a fixed rate with WithRateLimit. Responses and
+// errors are returned in a slice parallel to requests; a request that
+// fails does not prevent the others from completing. Requests that come
+// back OVER_QUERY_LIMIT are retried with the same exponential backoff as
+// RetryTransport before being reported as failed.
+func (c *Context) BatchGeocode(requests []*GeocodingRequest, opts ...BatchOption) ([]GeocodingResponse, []error) {
+	cfg := batchConfig{concurrency: DefaultBatchConcurrency}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.concurrency <= 0 {
+		cfg.concurrency = DefaultBatchConcurrency
+	}
+
+	limiter := newRateLimiter(cfg.qps)
+	defer limiter.stop()
+
+	responses := make([]GeocodingResponse, len(requests))
+	errs := make([]error, len(requests))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var done int32
+	for i := 0; i < cfg.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				resp, err := c.geocodeWithRetry(requests[i], limiter)
+				if err != nil {
+					errs[i] = err
+				} else {
+					responses[i] = *resp
+				}
+				if cfg.progress != nil {
+					cfg.progress(int(atomic.AddInt32(&done, 1)), len(requests))
+				}
+			}
+		}()
+	}
+	for i := range requests {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return responses, errs
+}
+
+// geocodeWithRetry issues r.Get(c), requeuing OVER_QUERY_LIMIT responses
+// with exponential backoff, up to DefaultMaxTries attempts.
+func (c *Context) geocodeWithRetry(r *GeocodingRequest, limiter *rateLimiter) (*GeocodingResponse, error) {
+	wait := DefaultInitialWait
+	var resp *GeocodingResponse
+	var err error
+	for try := 0; try < DefaultMaxTries; try++ {
+		limiter.wait()
+		resp, err = r.Get(c)
+
+		statusErr, overQueryLimit := err.(*StatusError)
+		if err == nil || !overQueryLimit || statusErr.Status != "OVER_QUERY_LIMIT" || try == DefaultMaxTries-1 {
+			return resp, err
+		}
+
+		time.Sleep(wait)
+		wait = backoffWait(wait, DefaultMaxWait)
+	}
+	return resp, err
+}
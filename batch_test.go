@@ -0,0 +1,120 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBatchGeocode(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		addr := r.URL.Query().Get("address")
+		fmt.Fprintf(w, `{"status":"OK","results":[{"formatted_address":%q}]}`, addr)
+	}))
+	defer server.Close()
+
+	ctx := NewContext("test-key", http.DefaultClient)
+	ctx.baseURL = server.URL
+
+	requests := make([]*GeocodingRequest, 10)
+	for i := range requests {
+		requests[i] = &GeocodingRequest{Address: fmt.Sprintf("address-%d", i)}
+	}
+
+	responses, errs := ctx.BatchGeocode(requests, WithConcurrency(4))
+
+	if got, want := atomic.LoadInt32(&calls), int32(10); got != want {
+		t.Errorf("server received %d requests, want %d", got, want)
+	}
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("requests[%d]: unexpected error %v", i, err)
+			continue
+		}
+		want := fmt.Sprintf("address-%d", i)
+		if got := responses[i].Results[0].FormattedAddress; got != want {
+			t.Errorf("responses[%d].FormattedAddress = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestBatchGeocodeReportsProgress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status":"OK","results":[{"formatted_address":"ok"}]}`)
+	}))
+	defer server.Close()
+
+	ctx := NewContext("test-key", http.DefaultClient)
+	ctx.baseURL = server.URL
+
+	requests := make([]*GeocodingRequest, 10)
+	for i := range requests {
+		requests[i] = &GeocodingRequest{Address: fmt.Sprintf("address-%d", i)}
+	}
+
+	var calls int32
+	var maxDone int32
+	ctx.BatchGeocode(requests, WithConcurrency(4), WithProgress(func(done, total int) {
+		atomic.AddInt32(&calls, 1)
+		if total != len(requests) {
+			t.Errorf("progress total = %d, want %d", total, len(requests))
+		}
+		for {
+			max := atomic.LoadInt32(&maxDone)
+			if int32(done) <= max || atomic.CompareAndSwapInt32(&maxDone, max, int32(done)) {
+				break
+			}
+		}
+	}))
+
+	if got, want := atomic.LoadInt32(&calls), int32(len(requests)); got != want {
+		t.Errorf("progress callback invoked %d times, want %d (one per request)", got, want)
+	}
+	if got, want := atomic.LoadInt32(&maxDone), int32(len(requests)); got != want {
+		t.Errorf("final progress done = %d, want %d", got, want)
+	}
+}
+
+func TestBatchGeocodeRetriesOverQueryLimit(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			fmt.Fprint(w, `{"status":"OVER_QUERY_LIMIT"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status":"OK","results":[{"formatted_address":"ok"}]}`)
+	}))
+	defer server.Close()
+
+	ctx := NewContext("test-key", http.DefaultClient)
+	ctx.baseURL = server.URL
+
+	resp, err := ctx.geocodeWithRetry(&GeocodingRequest{Address: "somewhere"}, nil)
+	if err != nil {
+		t.Fatalf("geocodeWithRetry returned error: %v", err)
+	}
+	if got, want := atomic.LoadInt32(&calls), int32(2); got != want {
+		t.Errorf("server received %d requests, want %d", got, want)
+	}
+	if got, want := resp.Results[0].FormattedAddress, "ok"; got != want {
+		t.Errorf("FormattedAddress = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Cache stores serialized Google Maps Web Service API responses, keyed by
+// an opaque string built from the request that produced them and the
+// Geocoder that will serve it. Put is advisory about ttl: implementations
+// that cannot expire entries may ignore it, at the cost of serving stale
+// data.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Put(key string, val []byte, ttl time.Duration)
+}
+
+// WithCache serves GeocodingRequests from cache when possible, storing
+// fresh responses for ttl. Identical requests - canonicalized by address,
+// lat/lng, bounds, components, language, region, result type and location
+// type -
+// share a cache entry only when served by the same Geocoder, since
+// different backends return different LocationType approximations and
+// address-component granularity for the same address. A single Cache can
+// safely be reused across Contexts configured with different Geocoders
+// (e.g. a persistent -cache-dir shared across -provider runs).
+func WithCache(cache Cache, ttl time.Duration) ContextOption {
+	return func(c *Context) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// cachedGeocode serves r from c.cache when present, falling back to fn and
+// populating the cache on a miss. With no cache configured it just calls fn.
+func (c *Context) cachedGeocode(r *GeocodingRequest, fn func(*GeocodingRequest) (*GeocodingResponse, error)) (*GeocodingResponse, error) {
+	if c.cache == nil {
+		return fn(r)
+	}
+
+	key := geocodingCacheKey(c.geocoder(), r)
+	if data, ok := c.cache.Get(key); ok {
+		var resp GeocodingResponse
+		if err := json.Unmarshal(data, &resp); err == nil {
+			return &resp, nil
+		}
+	}
+
+	resp, err := fn(r)
+	if err != nil {
+		return nil, err
+	}
+	if data, err := json.Marshal(resp); err == nil {
+		c.cache.Put(key, data, c.cacheTTL)
+	}
+	return resp, nil
+}
+
+// geocodingCacheKey canonicalizes the parts of r that affect the response,
+// namespaced by g's concrete type so that different Geocoders never share
+// a cache entry, into a stable, fixed-length key.
+func geocodingCacheKey(g Geocoder, r *GeocodingRequest) string {
+	components := append([]ComponentFilter(nil), r.Components...)
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Component != components[j].Component {
+			return components[i].Component < components[j].Component
+		}
+		return components[i].Value < components[j].Value
+	})
+
+	resultType := append([]string(nil), r.ResultType...)
+	sort.Strings(resultType)
+
+	locationType := append([]LocationType(nil), r.LocationType...)
+	sort.Slice(locationType, func(i, j int) bool { return locationType[i] < locationType[j] })
+
+	latlng := ""
+	if r.LatLng != nil {
+		latlng = r.LatLng.String()
+	}
+	bounds := ""
+	if r.Bounds != nil {
+		bounds = r.Bounds.String()
+	}
+
+	parts := []string{
+		"provider=" + fmt.Sprintf("%T", g),
+		"address=" + r.Address,
+		"latlng=" + latlng,
+		"bounds=" + bounds,
+		"components=" + serializeComponents(components),
+		"language=" + r.Language,
+		"region=" + r.Region,
+		"result_type=" + strings.Join(resultType, "|"),
+		"location_type=" + joinLocationTypes(locationType),
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "&")))
+	return hex.EncodeToString(sum[:])
+}
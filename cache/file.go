@@ -0,0 +1,100 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var cacheBucket = []byte("maps-cache")
+
+// FileStore is a BoltDB-backed maps.Cache that persists entries to a
+// single file on disk, so a geocoding cache can be shared across runs
+// (e.g. in CI) instead of being rebuilt in memory every time.
+type FileStore struct {
+	db *bolt.DB
+}
+
+type fileStoreEntry struct {
+	Value   []byte    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+// NewFileStore opens (creating if necessary) a BoltDB file at path.
+func NewFileStore(path string) (*FileStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(cacheBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &FileStore{db: db}, nil
+}
+
+// Get implements maps.Cache.
+func (f *FileStore) Get(key string) ([]byte, bool) {
+	var entry fileStoreEntry
+	var found bool
+	f.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(cacheBucket).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil
+		}
+		found = true
+		return nil
+	})
+	if !found {
+		return nil, false
+	}
+	if !entry.Expires.IsZero() && time.Now().After(entry.Expires) {
+		f.db.Update(func(tx *bolt.Tx) error {
+			return tx.Bucket(cacheBucket).Delete([]byte(key))
+		})
+		return nil, false
+	}
+	return entry.Value, true
+}
+
+// Put implements maps.Cache.
+func (f *FileStore) Put(key string, val []byte, ttl time.Duration) {
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+	data, err := json.Marshal(fileStoreEntry{Value: val, Expires: expires})
+	if err != nil {
+		return
+	}
+	f.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(cacheBucket).Put([]byte(key), data)
+	})
+}
+
+// Close closes the underlying BoltDB file.
+func (f *FileStore) Close() error {
+	return f.db.Close()
+}
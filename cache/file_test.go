@@ -0,0 +1,74 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileStoreGetPut(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+	defer store.Close()
+
+	if _, ok := store.Get("a"); ok {
+		t.Fatal("Get on empty store returned a hit")
+	}
+
+	store.Put("a", []byte("1"), 0)
+	if val, ok := store.Get("a"); !ok || string(val) != "1" {
+		t.Fatalf("Get(a) = %q, %v; want \"1\", true", val, ok)
+	}
+}
+
+func TestFileStorePersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.db")
+
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+	store.Put("a", []byte("1"), 0)
+	store.Close()
+
+	reopened, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("reopening NewFileStore returned error: %v", err)
+	}
+	defer reopened.Close()
+
+	if val, ok := reopened.Get("a"); !ok || string(val) != "1" {
+		t.Fatalf("Get(a) after reopen = %q, %v; want \"1\", true", val, ok)
+	}
+}
+
+func TestFileStoreExpires(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatalf("NewFileStore returned error: %v", err)
+	}
+	defer store.Close()
+
+	store.Put("a", []byte("1"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := store.Get("a"); ok {
+		t.Error("expired entry should not be returned")
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache provides maps.Cache implementations for caching Google
+// Maps Web Service API responses.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// DefaultLRUSize is the number of entries LRU holds when NewLRU is called
+// with maxItems <= 0.
+const DefaultLRUSize = 1000
+
+type lruEntry struct {
+	key     string
+	val     []byte
+	expires time.Time
+}
+
+// LRU is an in-memory, size-bounded, TTL-aware cache satisfying
+// maps.Cache. It is safe for concurrent use.
+type LRU struct {
+	mu       sync.Mutex
+	maxItems int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+// NewLRU builds an LRU that holds at most maxItems entries, evicting the
+// least recently used entry once full. maxItems <= 0 means DefaultLRUSize.
+func NewLRU(maxItems int) *LRU {
+	if maxItems <= 0 {
+		maxItems = DefaultLRUSize
+	}
+	return &LRU{
+		maxItems: maxItems,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get implements maps.Cache.
+func (c *LRU) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*lruEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		c.removeElement(el)
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	return e.val, true
+}
+
+// Put implements maps.Cache.
+func (c *LRU) Put(key string, val []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*lruEntry)
+		e.val, e.expires = val, expires
+		c.order.MoveToFront(el)
+		return
+	}
+
+	el := c.order.PushFront(&lruEntry{key: key, val: val, expires: expires})
+	c.items[key] = el
+
+	if c.order.Len() > c.maxItems {
+		c.removeElement(c.order.Back())
+	}
+}
+
+func (c *LRU) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*lruEntry).key)
+}
@@ -0,0 +1,61 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUGetPut(t *testing.T) {
+	c := NewLRU(2)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("Get on empty cache returned a hit")
+	}
+
+	c.Put("a", []byte("1"), 0)
+	if val, ok := c.Get("a"); !ok || string(val) != "1" {
+		t.Fatalf("Get(a) = %q, %v; want \"1\", true", val, ok)
+	}
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU(2)
+	c.Put("a", []byte("1"), 0)
+	c.Put("b", []byte("2"), 0)
+	c.Get("a") // touch a, making b the least recently used
+	c.Put("c", []byte("3"), 0)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Error("a should still be present")
+	}
+	if _, ok := c.Get("c"); !ok {
+		t.Error("c should be present")
+	}
+}
+
+func TestLRUExpires(t *testing.T) {
+	c := NewLRU(2)
+	c.Put("a", []byte("1"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Error("expired entry should not be returned")
+	}
+}
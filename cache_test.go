@@ -0,0 +1,126 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type memCache struct {
+	entries map[string][]byte
+}
+
+func (c *memCache) Get(key string) ([]byte, bool) {
+	v, ok := c.entries[key]
+	return v, ok
+}
+
+func (c *memCache) Put(key string, val []byte, ttl time.Duration) {
+	c.entries[key] = val
+}
+
+func TestGeocodingRequestGetUsesCache(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"status":"OK","results":[{"formatted_address":"Cached Ave"}]}`))
+	}))
+	defer server.Close()
+
+	c := &memCache{entries: make(map[string][]byte)}
+	ctx := NewContext("test-key", http.DefaultClient, WithCache(c, time.Minute))
+	ctx.baseURL = server.URL
+
+	r := &GeocodingRequest{Address: "1600 Amphitheatre Parkway"}
+
+	for i := 0; i < 3; i++ {
+		resp, err := r.Get(ctx)
+		if err != nil {
+			t.Fatalf("Get returned error: %v", err)
+		}
+		if got := resp.Results[0].FormattedAddress; got != "Cached Ave" {
+			t.Errorf("FormattedAddress = %q, want %q", got, "Cached Ave")
+		}
+	}
+
+	if got, want := atomic.LoadInt32(&calls), int32(1); got != want {
+		t.Errorf("server received %d requests, want %d (later calls should hit the cache)", got, want)
+	}
+}
+
+func TestGeocodingCacheKeyDistinguishesRequests(t *testing.T) {
+	a := &GeocodingRequest{Address: "x"}
+	b := &GeocodingRequest{Address: "x", Language: "fr"}
+
+	if geocodingCacheKey(googleGeocoder{}, a) == geocodingCacheKey(googleGeocoder{}, b) {
+		t.Error("requests differing only by Language produced the same cache key")
+	}
+}
+
+func TestGeocodingCacheKeyDistinguishesProvider(t *testing.T) {
+	r := &GeocodingRequest{Address: "x"}
+
+	if geocodingCacheKey(googleGeocoder{}, r) == geocodingCacheKey(NominatimGeocoder{}, r) {
+		t.Error("the same request against different Geocoders produced the same cache key")
+	}
+}
+
+func TestCacheNotSharedAcrossProviders(t *testing.T) {
+	googleCalls, nominatimCalls := 0, 0
+	googleServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		googleCalls++
+		w.Write([]byte(`{"status":"OK","results":[{"formatted_address":"Google Result"}]}`))
+	}))
+	defer googleServer.Close()
+	nominatimServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nominatimCalls++
+		w.Write([]byte(`[{"lat":"1","lon":"2","display_name":"Nominatim Result"}]`))
+	}))
+	defer nominatimServer.Close()
+
+	shared := &memCache{entries: make(map[string][]byte)}
+
+	googleCtx := NewContext("test-key", http.DefaultClient, WithCache(shared, time.Minute))
+	googleCtx.baseURL = googleServer.URL
+
+	nominatimCtx := NewContext("test-key", http.DefaultClient,
+		WithGeocoder(NominatimGeocoder{BaseURL: nominatimServer.URL}),
+		WithCache(shared, time.Minute))
+
+	r := &GeocodingRequest{Address: "1600 Amphitheatre Parkway"}
+
+	googleResp, err := r.Get(googleCtx)
+	if err != nil {
+		t.Fatalf("Get against googleCtx returned error: %v", err)
+	}
+	nominatimResp, err := r.Get(nominatimCtx)
+	if err != nil {
+		t.Fatalf("Get against nominatimCtx returned error: %v", err)
+	}
+
+	if got, want := googleResp.Results[0].FormattedAddress, "Google Result"; got != want {
+		t.Errorf("googleResp FormattedAddress = %q, want %q", got, want)
+	}
+	if got, want := nominatimResp.Results[0].FormattedAddress, "Nominatim Result"; got != want {
+		t.Errorf("nominatimResp FormattedAddress = %q, want %q", got, want)
+	}
+	if googleCalls != 1 || nominatimCalls != 1 {
+		t.Errorf("googleCalls = %d, nominatimCalls = %d, want 1 and 1 (providers must not share a cache entry)", googleCalls, nominatimCalls)
+	}
+}
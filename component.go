@@ -0,0 +1,44 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import "strings"
+
+// Component is a component filter for which you wish to obtain a geocode,
+// as defined in https://developers.google.com/maps/documentation/geocoding/intro#ComponentFiltering.
+type Component string
+
+// Supported values for Component.
+const (
+	ComponentRoute              = Component("route")
+	ComponentLocality           = Component("locality")
+	ComponentAdministrativeArea = Component("administrative_area")
+	ComponentPostalCode         = Component("postal_code")
+	ComponentCounty             = Component("country")
+)
+
+// ComponentFilter pairs a Component with the value to filter on.
+type ComponentFilter struct {
+	Component Component
+	Value     string
+}
+
+func serializeComponents(components []ComponentFilter) string {
+	s := make([]string, len(components))
+	for i, c := range components {
+		s[i] = string(c.Component) + ":" + c.Value
+	}
+	return strings.Join(s, "|")
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// baseURL is the scheme and host for the Google Maps Web Service APIs.
+const baseURL = "https://maps.googleapis.com"
+
+// Context carries the API key and HTTP client shared by every request made
+// against the Google Maps Web Service APIs.
+type Context struct {
+	apiKey   string
+	client   *http.Client
+	baseURL  string
+	g        Geocoder
+	cache    Cache
+	cacheTTL time.Duration
+}
+
+// ContextOption configures optional Context behavior, such as the backend
+// used to serve geocoding requests.
+type ContextOption func(*Context)
+
+// WithGeocoder overrides the Geocoder used to serve GeocodingRequests,
+// letting callers without a Google API key geocode through a different
+// backend such as NominatimGeocoder.
+func WithGeocoder(g Geocoder) ContextOption {
+	return func(c *Context) { c.g = g }
+}
+
+// NewContext builds a Context that authenticates with apiKey and issues
+// requests using client. If client is nil, http.DefaultClient is used.
+func NewContext(apiKey string, client *http.Client, opts ...ContextOption) *Context {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	c := &Context{
+		apiKey:  apiKey,
+		client:  client,
+		baseURL: baseURL,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Configure applies additional ContextOptions to c, letting callers layer
+// options (such as WithCache) on top of a Context returned by
+// NewContextWithRetry.
+func (c *Context) Configure(opts ...ContextOption) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// getJSON issues a GET request against path with the given query parameters,
+// authenticates it with the context's API key, and decodes the JSON
+// response body into out.
+func (c *Context) getJSON(path string, q url.Values, out interface{}) error {
+	if q == nil {
+		q = make(url.Values)
+	}
+	q.Set("key", c.apiKey)
+
+	req, err := http.NewRequest("GET", c.baseURL+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,31 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import "fmt"
+
+// StatusError is returned when the Google Maps Web Service API responds
+// with a status other than "OK" or "ZERO_RESULTS".
+type StatusError struct {
+	Status       string
+	ErrorMessage string
+}
+
+func (e *StatusError) Error() string {
+	if e.ErrorMessage != "" {
+		return fmt.Sprintf("maps: status %s: %s", e.Status, e.ErrorMessage)
+	}
+	return fmt.Sprintf("maps: status %s", e.Status)
+}
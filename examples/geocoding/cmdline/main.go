@@ -17,20 +17,26 @@
 package main
 
 import (
+	"bufio"
+	"encoding/csv"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kr/pretty"
 	"google.golang.org/maps"
+	"google.golang.org/maps/cache"
 )
 
 var (
 	apiKey       = flag.String("key", "", "API Key for using Google Maps API.")
+	provider     = flag.String("provider", "google", "Geocoding backend to use: google, nominatim or photon.")
 	address      = flag.String("address", "", "The street address that you want to geocode, in the format used by the national postal service of the country concerned.")
 	components   = flag.String("components", "", "A component filter for which you wish to obtain a geocode.")
 	bounds       = flag.String("bounds", "", "The bounding box of the viewport within which to bias geocode results more prominently.")
@@ -39,6 +45,11 @@ var (
 	latlng       = flag.String("latlng", "", "The textual latitude/longitude value for which you wish to obtain the closest, human-readable address.")
 	resultType   = flag.String("result_type", "", "One or more address types, separated by a pipe (|).")
 	locationType = flag.String("location_type", "", "One or more location types, separated by a pipe (|).")
+	input        = flag.String("input", "", "Path to a file with one address per line to batch-geocode; writes CSV to stdout.")
+	concurrency  = flag.Int("concurrency", maps.DefaultBatchConcurrency, "Concurrent requests to issue in -input mode.")
+	rateLimit    = flag.Float64("rate_limit", 0, "Maximum requests per second in -input mode (0 means unlimited).")
+	cacheDir     = flag.String("cache-dir", "", "Directory for an on-disk geocode cache; empty disables caching.")
+	cacheTTL     = flag.Duration("cache-ttl", time.Hour, "How long cached geocoding responses remain valid.")
 )
 
 func usageAndExit(msg string) {
@@ -51,10 +62,17 @@ func usageAndExit(msg string) {
 func main() {
 	flag.Parse()
 	client := &http.Client{}
-	if *apiKey == "" {
+	if *provider == "google" && *apiKey == "" {
 		usageAndExit("Please specify an API Key.")
 	}
-	ctx := maps.NewContext(*apiKey, client)
+
+	ctx := newContext(client)
+
+	if *input != "" {
+		runBatch(ctx)
+		return
+	}
+
 	r := &maps.GeocodingRequest{
 		Address:  *address,
 		Language: *language,
@@ -81,6 +99,94 @@ func main() {
 	pretty.Println(resp)
 }
 
+func newContext(client *http.Client) *maps.Context {
+	var ctx *maps.Context
+	switch *provider {
+	case "nominatim":
+		ctx = maps.NewContext(*apiKey, client, maps.WithGeocoder(maps.NominatimGeocoder{}))
+	case "photon":
+		ctx = maps.NewContext(*apiKey, client, maps.WithGeocoder(maps.PhotonGeocoder{}))
+	default:
+		ctx = maps.NewContextWithRetry(*apiKey, client)
+	}
+
+	if *cacheDir != "" {
+		store, err := cache.NewFileStore(filepath.Join(*cacheDir, "geocode.db"))
+		if err != nil {
+			log.Fatalf("error opening cache: %v", err)
+		}
+		ctx.Configure(maps.WithCache(store, *cacheTTL))
+	}
+
+	return ctx
+}
+
+// runBatch reads one address per line from *input, geocodes them all
+// concurrently, and writes the results as CSV to stdout.
+func runBatch(ctx *maps.Context) {
+	addresses, err := readLines(*input)
+	if err != nil {
+		log.Fatalf("error reading %s: %v", *input, err)
+	}
+
+	requests := make([]*maps.GeocodingRequest, len(addresses))
+	for i, a := range addresses {
+		requests[i] = &maps.GeocodingRequest{Address: a}
+	}
+
+	fmt.Fprintf(os.Stderr, "geocoding %d addresses...\n", len(requests))
+	responses, errs := ctx.BatchGeocode(requests,
+		maps.WithConcurrency(*concurrency),
+		maps.WithRateLimit(*rateLimit),
+		maps.WithProgress(func(done, total int) {
+			fmt.Fprintf(os.Stderr, "\r%d/%d geocoded", done, total)
+		}))
+	fmt.Fprintf(os.Stderr, "\ndone.\n")
+
+	w := csv.NewWriter(os.Stdout)
+	defer w.Flush()
+	w.Write([]string{"input", "lat", "lng", "formatted_address", "location_type"})
+
+	for i, resp := range responses {
+		if errs[i] != nil {
+			log.Printf("error geocoding %q: %v", addresses[i], errs[i])
+			w.Write([]string{addresses[i], "", "", "", ""})
+			continue
+		}
+		if len(resp.Results) == 0 {
+			w.Write([]string{addresses[i], "", "", "", ""})
+			continue
+		}
+		result := resp.Results[0]
+		w.Write([]string{
+			addresses[i],
+			strconv.FormatFloat(result.Geometry.Location.Lat, 'f', -1, 64),
+			strconv.FormatFloat(result.Geometry.Location.Lng, 'f', -1, 64),
+			result.FormattedAddress,
+			string(result.Geometry.LocationType),
+		})
+	}
+}
+
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
 func parseComponents(components string, r *maps.GeocodingRequest) {
 	if components != "" {
 		c := strings.Split(components, "|")
@@ -0,0 +1,71 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import "net/http"
+
+// Geocoder is the interface implemented by every geocoding backend. The
+// Google Maps Geocoding API is the default; other implementations (such as
+// NominatimGeocoder) let callers without a Google API key still geocode.
+type Geocoder interface {
+	Geocode(ctx *Context, r *GeocodingRequest) (*GeocodingResponse, error)
+	ReverseGeocode(ctx *Context, r *GeocodingRequest) (*GeocodingResponse, error)
+}
+
+// clientConfigurable is implemented by Geocoders (such as
+// NominatimGeocoder and PhotonGeocoder) that make their own HTTP requests
+// rather than going through Context.getJSON, so they can inherit the
+// Context's client when they weren't given one of their own.
+type clientConfigurable interface {
+	withClient(*http.Client) Geocoder
+}
+
+// geocoder returns the Context's configured Geocoder, defaulting to the
+// Google Maps Geocoding API. A Geocoder that hasn't been given its own
+// *http.Client inherits the one passed to NewContext, so WithGeocoder
+// doesn't silently drop a caller's client customization (timeouts,
+// proxies, a retry transport).
+func (c *Context) geocoder() Geocoder {
+	g := c.g
+	if g == nil {
+		g = googleGeocoder{}
+	}
+	if cc, ok := g.(clientConfigurable); ok {
+		g = cc.withClient(c.client)
+	}
+	return g
+}
+
+// googleGeocoder implements Geocoder against the Google Maps Geocoding API.
+type googleGeocoder struct{}
+
+func (googleGeocoder) Geocode(ctx *Context, r *GeocodingRequest) (*GeocodingResponse, error) {
+	q, err := r.values()
+	if err != nil {
+		return nil, err
+	}
+	var resp geocodingAPIResponse
+	if err := ctx.getJSON(geocodingAPI, q, &resp); err != nil {
+		return nil, err
+	}
+	if resp.Status != "OK" && resp.Status != "ZERO_RESULTS" {
+		return nil, &StatusError{Status: resp.Status, ErrorMessage: resp.ErrorMessage}
+	}
+	return &GeocodingResponse{Results: resp.Results}, nil
+}
+
+func (g googleGeocoder) ReverseGeocode(ctx *Context, r *GeocodingRequest) (*GeocodingResponse, error) {
+	return g.Geocode(ctx, r)
+}
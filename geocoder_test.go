@@ -0,0 +1,78 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// countingTransport counts the RoundTrips it serves, so tests can tell
+// whether a Context's client was actually used to make a request.
+type countingTransport struct {
+	calls int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestWithGeocoderInheritsContextClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	transport := &countingTransport{}
+	client := &http.Client{Transport: transport}
+
+	ctx := NewContext("test-key", client, WithGeocoder(NominatimGeocoder{BaseURL: server.URL}))
+
+	if _, err := (&GeocodingRequest{Address: "somewhere"}).Get(ctx); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+
+	if transport.calls != 1 {
+		t.Errorf("client's transport saw %d calls, want 1 (WithGeocoder must not drop the Context's client)", transport.calls)
+	}
+}
+
+func TestWithGeocoderRespectsGeocoderOwnClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	contextTransport := &countingTransport{}
+	geocoderTransport := &countingTransport{}
+
+	ctx := NewContext("test-key", &http.Client{Transport: contextTransport}, WithGeocoder(NominatimGeocoder{
+		BaseURL: server.URL,
+		Client:  &http.Client{Transport: geocoderTransport},
+	}))
+
+	if _, err := (&GeocodingRequest{Address: "somewhere"}).Get(ctx); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+
+	if geocoderTransport.calls != 1 {
+		t.Errorf("geocoder's own transport saw %d calls, want 1", geocoderTransport.calls)
+	}
+	if contextTransport.calls != 0 {
+		t.Errorf("context transport saw %d calls, want 0 (geocoder's own Client should win)", contextTransport.calls)
+	}
+}
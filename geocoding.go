@@ -0,0 +1,121 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+const geocodingAPI = "/maps/api/geocode/json"
+
+// GeocodingRequest is the set of parameters accepted by the Geocoding API
+// and its reverse-geocoding counterpart. Address selects forward geocoding,
+// LatLng selects reverse geocoding; if both are set, Address is used. A
+// request with only Components set is also accepted.
+type GeocodingRequest struct {
+	Address      string
+	Components   []ComponentFilter
+	Bounds       *LatLngBounds
+	Language     string
+	Region       string
+	LatLng       *LatLng
+	ResultType   []string
+	LocationType []LocationType
+}
+
+// AddComponentFilter appends a component filter to the request.
+func (r *GeocodingRequest) AddComponentFilter(c Component, value string) {
+	r.Components = append(r.Components, ComponentFilter{Component: c, Value: value})
+}
+
+// GeocodingResult is a single result returned by the Geocoding API.
+type GeocodingResult struct {
+	AddressComponents []AddressComponent `json:"address_components"`
+	FormattedAddress  string             `json:"formatted_address"`
+	Geometry          Geometry           `json:"geometry"`
+	Types             []string           `json:"types"`
+	PlaceID           string             `json:"place_id"`
+	PartialMatch      bool               `json:"partial_match"`
+}
+
+// GeocodingResponse is the decoded body of a Geocoding API response.
+type GeocodingResponse struct {
+	Results []GeocodingResult `json:"results"`
+}
+
+type geocodingAPIResponse struct {
+	Results      []GeocodingResult `json:"results"`
+	Status       string            `json:"status"`
+	ErrorMessage string            `json:"error_message"`
+}
+
+func (r *GeocodingRequest) values() (url.Values, error) {
+	q := make(url.Values)
+	switch {
+	case r.Address != "":
+		q.Set("address", r.Address)
+	case r.LatLng != nil:
+		q.Set("latlng", r.LatLng.String())
+	case len(r.Components) > 0:
+		// Components-only lookups are allowed.
+	default:
+		return nil, errors.New("maps: geocoding request must specify Address, LatLng or Components")
+	}
+	if len(r.Components) > 0 {
+		q.Set("components", serializeComponents(r.Components))
+	}
+	if r.Bounds != nil {
+		q.Set("bounds", r.Bounds.String())
+	}
+	if r.Language != "" {
+		q.Set("language", r.Language)
+	}
+	if r.Region != "" {
+		q.Set("region", r.Region)
+	}
+	if len(r.ResultType) > 0 {
+		q.Set("result_type", strings.Join(r.ResultType, "|"))
+	}
+	if len(r.LocationType) > 0 {
+		q.Set("location_type", joinLocationTypes(r.LocationType))
+	}
+	return q, nil
+}
+
+// Get issues the geocoding request against ctx's configured Geocoder,
+// defaulting to the Google Maps Geocoding API. A request with LatLng and
+// no Address is reverse geocoded. If ctx has a Cache (see WithCache),
+// identical requests are served from it instead.
+func (r *GeocodingRequest) Get(ctx *Context) (*GeocodingResponse, error) {
+	g := ctx.geocoder()
+	return ctx.cachedGeocode(r, func(req *GeocodingRequest) (*GeocodingResponse, error) {
+		if req.Address == "" && req.LatLng != nil {
+			return g.ReverseGeocode(ctx, req)
+		}
+		return g.Geocode(ctx, req)
+	})
+}
+
+// ReverseGeocode issues the request against ctx's configured Geocoder. It
+// is equivalent to Get, but documents the intent when r.LatLng is set
+// instead of r.Address.
+func (r *GeocodingRequest) ReverseGeocode(ctx *Context) (*GeocodingResponse, error) {
+	g := ctx.geocoder()
+	return ctx.cachedGeocode(r, func(req *GeocodingRequest) (*GeocodingResponse, error) {
+		return g.ReverseGeocode(ctx, req)
+	})
+}
@@ -0,0 +1,256 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// DefaultNominatimBaseURL is the public OpenStreetMap Nominatim instance.
+// Its usage policy requires a descriptive User-Agent and a low request
+// rate; self-hosted or commercial deployments should set
+// NominatimGeocoder.BaseURL accordingly.
+const DefaultNominatimBaseURL = "https://nominatim.openstreetmap.org"
+
+// NominatimGeocoder implements Geocoder against an OpenStreetMap Nominatim
+// instance, so that callers without a Google API key can still geocode.
+type NominatimGeocoder struct {
+	// BaseURL defaults to DefaultNominatimBaseURL.
+	BaseURL string
+	// UserAgent is sent on every request, as required by Nominatim's
+	// usage policy. It defaults to "maps.Geocoder".
+	UserAgent string
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+type nominatimAddress struct {
+	Road        string `json:"road"`
+	HouseNumber string `json:"house_number"`
+	City        string `json:"city"`
+	Town        string `json:"town"`
+	Village     string `json:"village"`
+	State       string `json:"state"`
+	Postcode    string `json:"postcode"`
+	Country     string `json:"country"`
+	CountryCode string `json:"country_code"`
+}
+
+type nominatimResult struct {
+	PlaceID     int64            `json:"place_id"`
+	Lat         string           `json:"lat"`
+	Lon         string           `json:"lon"`
+	DisplayName string           `json:"display_name"`
+	Class       string           `json:"class"`
+	Type        string           `json:"type"`
+	Importance  float64          `json:"importance"`
+	BoundingBox []string         `json:"boundingbox"`
+	Address     nominatimAddress `json:"address"`
+}
+
+func (g NominatimGeocoder) baseURL() string {
+	if g.BaseURL != "" {
+		return g.BaseURL
+	}
+	return DefaultNominatimBaseURL
+}
+
+func (g NominatimGeocoder) userAgent() string {
+	if g.UserAgent != "" {
+		return g.UserAgent
+	}
+	return "maps.Geocoder"
+}
+
+func (g NominatimGeocoder) client() *http.Client {
+	if g.Client != nil {
+		return g.Client
+	}
+	return http.DefaultClient
+}
+
+// withClient returns g with Client set to client, unless g already has one
+// of its own. It implements clientConfigurable.
+func (g NominatimGeocoder) withClient(client *http.Client) Geocoder {
+	if g.Client == nil {
+		g.Client = client
+	}
+	return g
+}
+
+// lngLat formats a LatLng as "lng,lat", the corner format Nominatim's
+// viewbox parameter expects.
+func lngLat(l LatLng) string {
+	return strconv.FormatFloat(l.Lng, 'f', -1, 64) + "," + strconv.FormatFloat(l.Lat, 'f', -1, 64)
+}
+
+func (g NominatimGeocoder) values(r *GeocodingRequest) url.Values {
+	q := make(url.Values)
+	q.Set("format", "json")
+	q.Set("addressdetails", "1")
+
+	if r.Address != "" {
+		q.Set("q", r.Address)
+	}
+	for _, c := range r.Components {
+		switch c.Component {
+		case ComponentCounty:
+			q.Set("country", c.Value)
+		case ComponentPostalCode:
+			q.Set("postalcode", c.Value)
+		case ComponentLocality:
+			q.Set("city", c.Value)
+		}
+	}
+	if r.Bounds != nil {
+		// Nominatim's viewbox is <x1>,<y1>,<x2>,<y2>, i.e. lng,lat per
+		// corner - unlike LatLng.String(), which is lat,lng for Google's
+		// bounds param.
+		q.Set("viewbox", lngLat(r.Bounds.SouthWest)+","+lngLat(r.Bounds.NorthEast))
+		q.Set("bounded", "1")
+	}
+	if r.Language != "" {
+		q.Set("accept-language", r.Language)
+	}
+	if r.Region != "" {
+		q.Set("countrycodes", r.Region)
+	}
+	return q
+}
+
+func (g NominatimGeocoder) do(path string, q url.Values) ([]nominatimResult, error) {
+	req, err := http.NewRequest("GET", g.baseURL()+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", g.userAgent())
+
+	resp, err := g.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var results []nominatimResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Geocode implements Geocoder.
+func (g NominatimGeocoder) Geocode(ctx *Context, r *GeocodingRequest) (*GeocodingResponse, error) {
+	results, err := g.do("/search", g.values(r))
+	if err != nil {
+		return nil, err
+	}
+	return &GeocodingResponse{Results: toGeocodingResults(results)}, nil
+}
+
+// ReverseGeocode implements Geocoder.
+func (g NominatimGeocoder) ReverseGeocode(ctx *Context, r *GeocodingRequest) (*GeocodingResponse, error) {
+	if r.LatLng == nil {
+		return g.Geocode(ctx, r)
+	}
+	q := g.values(r)
+	q.Set("lat", strconv.FormatFloat(r.LatLng.Lat, 'f', -1, 64))
+	q.Set("lon", strconv.FormatFloat(r.LatLng.Lng, 'f', -1, 64))
+
+	result, err := g.do("/reverse", q)
+	if err != nil {
+		return nil, err
+	}
+	return &GeocodingResponse{Results: toGeocodingResults(result)}, nil
+}
+
+func toGeocodingResults(results []nominatimResult) []GeocodingResult {
+	out := make([]GeocodingResult, 0, len(results))
+	for _, r := range results {
+		lat, _ := strconv.ParseFloat(r.Lat, 64)
+		lng, _ := strconv.ParseFloat(r.Lon, 64)
+
+		out = append(out, GeocodingResult{
+			FormattedAddress:  r.DisplayName,
+			AddressComponents: nominatimAddressComponents(r.Address),
+			Types:             []string{r.Class, r.Type},
+			PlaceID:           strconv.FormatInt(r.PlaceID, 10),
+			Geometry: Geometry{
+				Location:     LatLng{Lat: lat, Lng: lng},
+				LocationType: approximateLocationType(r.Class, r.Type, r.Importance),
+				Viewport:     nominatimViewport(r.BoundingBox),
+			},
+		})
+	}
+	return out
+}
+
+func nominatimAddressComponents(a nominatimAddress) []AddressComponent {
+	var components []AddressComponent
+	add := func(name string, types ...string) {
+		if name == "" {
+			return
+		}
+		components = append(components, AddressComponent{LongName: name, ShortName: name, Types: types})
+	}
+	add(a.HouseNumber, "street_number")
+	add(a.Road, "route")
+	switch {
+	case a.City != "":
+		add(a.City, "locality")
+	case a.Town != "":
+		add(a.Town, "locality")
+	case a.Village != "":
+		add(a.Village, "locality")
+	}
+	add(a.State, "administrative_area_level_1")
+	add(a.Postcode, "postal_code")
+	add(a.Country, "country")
+	return components
+}
+
+func nominatimViewport(bbox []string) LatLngBounds {
+	if len(bbox) != 4 {
+		return LatLngBounds{}
+	}
+	south, _ := strconv.ParseFloat(bbox[0], 64)
+	north, _ := strconv.ParseFloat(bbox[1], 64)
+	west, _ := strconv.ParseFloat(bbox[2], 64)
+	east, _ := strconv.ParseFloat(bbox[3], 64)
+	return LatLngBounds{
+		SouthWest: LatLng{Lat: south, Lng: west},
+		NorthEast: LatLng{Lat: north, Lng: east},
+	}
+}
+
+// approximateLocationType maps an OSM class/type/importance tuple (shared
+// by Nominatim and Photon, whose osm_key/osm_value mean the same thing)
+// onto the closest Google LocationType.
+func approximateLocationType(class, typ string, importance float64) LocationType {
+	switch {
+	case class == "building" || typ == "house":
+		return LocationTypeRooftop
+	case typ == "administrative" || class == "boundary":
+		return LocationTypeGeometricCenter
+	case class == "place" && (typ == "city" || typ == "town" || typ == "village" || typ == "suburb"):
+		return LocationTypeGeometricCenter
+	case importance >= 0.6:
+		return LocationTypeRangeInterpolated
+	default:
+		return LocationTypeApproximate
+	}
+}
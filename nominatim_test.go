@@ -0,0 +1,113 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNominatimGeocoderGeocode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("q"); got != "1600 Amphitheatre Parkway" {
+			t.Errorf("q = %q, want %q", got, "1600 Amphitheatre Parkway")
+		}
+		w.Write([]byte(`[{
+			"place_id": 42,
+			"lat": "37.4224",
+			"lon": "-122.0842",
+			"display_name": "1600 Amphitheatre Parkway, Mountain View, CA, USA",
+			"class": "building",
+			"type": "house",
+			"importance": 0.8,
+			"boundingbox": ["37.42", "37.43", "-122.09", "-122.08"],
+			"address": {
+				"house_number": "1600",
+				"road": "Amphitheatre Parkway",
+				"city": "Mountain View",
+				"state": "California",
+				"postcode": "94043",
+				"country": "United States"
+			}
+		}]`))
+	}))
+	defer server.Close()
+
+	g := NominatimGeocoder{BaseURL: server.URL}
+	resp, err := g.Geocode(nil, &GeocodingRequest{Address: "1600 Amphitheatre Parkway"})
+	if err != nil {
+		t.Fatalf("Geocode returned error: %v", err)
+	}
+	if len(resp.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(resp.Results))
+	}
+
+	result := resp.Results[0]
+	if result.PlaceID != "42" {
+		t.Errorf("PlaceID = %q, want %q", result.PlaceID, "42")
+	}
+	if result.Geometry.LocationType != LocationTypeRooftop {
+		t.Errorf("LocationType = %q, want %q", result.Geometry.LocationType, LocationTypeRooftop)
+	}
+	if len(result.AddressComponents) == 0 {
+		t.Error("expected address components, got none")
+	}
+}
+
+func TestNominatimGeocoderBoundsViewbox(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "-122.1,37.4,-122,37.5"
+		if got := r.URL.Query().Get("viewbox"); got != want {
+			t.Errorf("viewbox = %q, want %q (lng,lat per corner)", got, want)
+		}
+		if got := r.URL.Query().Get("bounded"); got != "1" {
+			t.Errorf("bounded = %q, want %q", got, "1")
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	g := NominatimGeocoder{BaseURL: server.URL}
+	_, err := g.Geocode(nil, &GeocodingRequest{
+		Address: "somewhere",
+		Bounds: &LatLngBounds{
+			SouthWest: LatLng{Lat: 37.4, Lng: -122.1},
+			NorthEast: LatLng{Lat: 37.5, Lng: -122.0},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Geocode returned error: %v", err)
+	}
+}
+
+func TestApproximateLocationType(t *testing.T) {
+	tests := []struct {
+		class, typ string
+		importance float64
+		want       LocationType
+	}{
+		{"building", "house", 0, LocationTypeRooftop},
+		{"boundary", "administrative", 0, LocationTypeGeometricCenter},
+		{"place", "city", 0, LocationTypeGeometricCenter},
+		{"highway", "residential", 0.7, LocationTypeRangeInterpolated},
+		{"highway", "residential", 0.1, LocationTypeApproximate},
+	}
+	for _, tt := range tests {
+		if got := approximateLocationType(tt.class, tt.typ, tt.importance); got != tt.want {
+			t.Errorf("approximateLocationType(%q, %q, %v) = %q, want %q", tt.class, tt.typ, tt.importance, got, tt.want)
+		}
+	}
+}
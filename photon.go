@@ -0,0 +1,169 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPhotonBaseURL is komoot's public Photon instance, a geocoder built
+// on top of OpenStreetMap data.
+const DefaultPhotonBaseURL = "https://photon.komoot.io"
+
+// PhotonGeocoder implements Geocoder against a Photon instance.
+type PhotonGeocoder struct {
+	// BaseURL defaults to DefaultPhotonBaseURL.
+	BaseURL string
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+type photonProperties struct {
+	Name        string `json:"name"`
+	Street      string `json:"street"`
+	HouseNumber string `json:"housenumber"`
+	City        string `json:"city"`
+	State       string `json:"state"`
+	Postcode    string `json:"postcode"`
+	Country     string `json:"country"`
+	OSMKey      string `json:"osm_key"`
+	OSMValue    string `json:"osm_value"`
+}
+
+type photonFeature struct {
+	Properties photonProperties `json:"properties"`
+	Geometry   struct {
+		Coordinates []float64 `json:"coordinates"` // [lng, lat]
+	} `json:"geometry"`
+}
+
+type photonResponse struct {
+	Features []photonFeature `json:"features"`
+}
+
+func (g PhotonGeocoder) baseURL() string {
+	if g.BaseURL != "" {
+		return g.BaseURL
+	}
+	return DefaultPhotonBaseURL
+}
+
+func (g PhotonGeocoder) client() *http.Client {
+	if g.Client != nil {
+		return g.Client
+	}
+	return http.DefaultClient
+}
+
+// withClient returns g with Client set to client, unless g already has one
+// of its own. It implements clientConfigurable.
+func (g PhotonGeocoder) withClient(client *http.Client) Geocoder {
+	if g.Client == nil {
+		g.Client = client
+	}
+	return g
+}
+
+func (g PhotonGeocoder) fetch(path string, q url.Values) (*photonResponse, error) {
+	req, err := http.NewRequest("GET", g.baseURL()+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.client().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var out photonResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Geocode implements Geocoder.
+func (g PhotonGeocoder) Geocode(ctx *Context, r *GeocodingRequest) (*GeocodingResponse, error) {
+	q := make(url.Values)
+	q.Set("q", r.Address)
+	if r.Language != "" {
+		q.Set("lang", r.Language)
+	}
+	resp, err := g.fetch("/api/", q)
+	if err != nil {
+		return nil, err
+	}
+	return &GeocodingResponse{Results: photonGeocodingResults(resp)}, nil
+}
+
+// ReverseGeocode implements Geocoder.
+func (g PhotonGeocoder) ReverseGeocode(ctx *Context, r *GeocodingRequest) (*GeocodingResponse, error) {
+	if r.LatLng == nil {
+		return g.Geocode(ctx, r)
+	}
+	q := make(url.Values)
+	q.Set("lat", strconv.FormatFloat(r.LatLng.Lat, 'f', -1, 64))
+	q.Set("lon", strconv.FormatFloat(r.LatLng.Lng, 'f', -1, 64))
+	if r.Language != "" {
+		q.Set("lang", r.Language)
+	}
+	resp, err := g.fetch("/reverse", q)
+	if err != nil {
+		return nil, err
+	}
+	return &GeocodingResponse{Results: photonGeocodingResults(resp)}, nil
+}
+
+func photonGeocodingResults(resp *photonResponse) []GeocodingResult {
+	out := make([]GeocodingResult, 0, len(resp.Features))
+	for _, f := range resp.Features {
+		p := f.Properties
+		var lat, lng float64
+		if len(f.Geometry.Coordinates) == 2 {
+			lng, lat = f.Geometry.Coordinates[0], f.Geometry.Coordinates[1]
+		}
+
+		out = append(out, GeocodingResult{
+			FormattedAddress:  p.Name,
+			AddressComponents: photonAddressComponents(p),
+			Types:             []string{p.OSMKey, p.OSMValue},
+			Geometry: Geometry{
+				Location:     LatLng{Lat: lat, Lng: lng},
+				LocationType: approximateLocationType(p.OSMKey, p.OSMValue, 0),
+			},
+		})
+	}
+	return out
+}
+
+func photonAddressComponents(p photonProperties) []AddressComponent {
+	var components []AddressComponent
+	add := func(name string, types ...string) {
+		if name == "" {
+			return
+		}
+		components = append(components, AddressComponent{LongName: name, ShortName: name, Types: types})
+	}
+	add(p.HouseNumber, "street_number")
+	add(p.Street, "route")
+	add(p.City, "locality")
+	add(p.State, "administrative_area_level_1")
+	add(p.Postcode, "postal_code")
+	add(p.Country, "country")
+	return components
+}
@@ -0,0 +1,122 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPhotonGeocoderGeocode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/" {
+			t.Errorf("path = %q, want %q", r.URL.Path, "/api/")
+		}
+		if got := r.URL.Query().Get("q"); got != "1600 Amphitheatre Parkway" {
+			t.Errorf("q = %q, want %q", got, "1600 Amphitheatre Parkway")
+		}
+		w.Write([]byte(`{"features":[{
+			"geometry": {"coordinates": [-122.0842, 37.4224]},
+			"properties": {
+				"name": "1600 Amphitheatre Parkway, Mountain View, CA, USA",
+				"housenumber": "1600",
+				"street": "Amphitheatre Parkway",
+				"city": "Mountain View",
+				"state": "California",
+				"postcode": "94043",
+				"country": "United States",
+				"osm_key": "building",
+				"osm_value": "house"
+			}
+		}]}`))
+	}))
+	defer server.Close()
+
+	g := PhotonGeocoder{BaseURL: server.URL}
+	resp, err := g.Geocode(nil, &GeocodingRequest{Address: "1600 Amphitheatre Parkway"})
+	if err != nil {
+		t.Fatalf("Geocode returned error: %v", err)
+	}
+	if len(resp.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(resp.Results))
+	}
+
+	result := resp.Results[0]
+	if got, want := result.Geometry.Location, (LatLng{Lat: 37.4224, Lng: -122.0842}); got != want {
+		t.Errorf("Location = %+v, want %+v (coordinates are [lng, lat] in GeoJSON)", got, want)
+	}
+	if result.Geometry.LocationType != LocationTypeRooftop {
+		t.Errorf("LocationType = %q, want %q", result.Geometry.LocationType, LocationTypeRooftop)
+	}
+	if len(result.AddressComponents) == 0 {
+		t.Error("expected address components, got none")
+	}
+}
+
+func TestPhotonGeocoderReverseGeocode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/reverse" {
+			t.Errorf("path = %q, want %q", r.URL.Path, "/reverse")
+		}
+		if got, want := r.URL.Query().Get("lat"), "37.4224"; got != want {
+			t.Errorf("lat = %q, want %q", got, want)
+		}
+		if got, want := r.URL.Query().Get("lon"), "-122.0842"; got != want {
+			t.Errorf("lon = %q, want %q", got, want)
+		}
+		w.Write([]byte(`{"features":[{
+			"geometry": {"coordinates": [-122.0842, 37.4224]},
+			"properties": {"name": "Somewhere", "osm_key": "place", "osm_value": "city"}
+		}]}`))
+	}))
+	defer server.Close()
+
+	g := PhotonGeocoder{BaseURL: server.URL}
+	resp, err := g.ReverseGeocode(nil, &GeocodingRequest{LatLng: &LatLng{Lat: 37.4224, Lng: -122.0842}})
+	if err != nil {
+		t.Fatalf("ReverseGeocode returned error: %v", err)
+	}
+	if len(resp.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(resp.Results))
+	}
+	if got, want := resp.Results[0].FormattedAddress, "Somewhere"; got != want {
+		t.Errorf("FormattedAddress = %q, want %q", got, want)
+	}
+}
+
+func TestPhotonAddressComponents(t *testing.T) {
+	p := photonProperties{
+		HouseNumber: "1600",
+		Street:      "Amphitheatre Parkway",
+		City:        "Mountain View",
+		State:       "California",
+		Postcode:    "94043",
+		Country:     "United States",
+	}
+	components := photonAddressComponents(p)
+	if len(components) != 6 {
+		t.Fatalf("got %d components, want 6", len(components))
+	}
+
+	p = photonProperties{City: "Mountain View"}
+	components = photonAddressComponents(p)
+	if len(components) != 1 {
+		t.Fatalf("got %d components for a partial address, want 1", len(components))
+	}
+	if components[0].LongName != "Mountain View" {
+		t.Errorf("LongName = %q, want %q", components[0].LongName, "Mountain View")
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import "time"
+
+// rateLimiter is a token-bucket limiter used to cap outgoing requests per
+// second. A nil *rateLimiter is a valid, unlimited limiter.
+type rateLimiter struct {
+	tokens chan struct{}
+	done   chan struct{}
+}
+
+// newRateLimiter returns a rateLimiter that allows at most qps requests per
+// second, or nil if qps is not positive.
+func newRateLimiter(qps float64) *rateLimiter {
+	if qps <= 0 {
+		return nil
+	}
+
+	rl := &rateLimiter{
+		tokens: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	go rl.run(time.Duration(float64(time.Second) / qps))
+	return rl
+}
+
+func (rl *rateLimiter) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			select {
+			case rl.tokens <- struct{}{}:
+			default:
+			}
+		case <-rl.done:
+			return
+		}
+	}
+}
+
+// wait blocks until a token is available.
+func (rl *rateLimiter) wait() {
+	if rl == nil {
+		return
+	}
+	<-rl.tokens
+}
+
+// stop releases the limiter's background goroutine.
+func (rl *rateLimiter) stop() {
+	if rl == nil {
+		return
+	}
+	close(rl.done)
+}
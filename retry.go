@@ -0,0 +1,214 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// Defaults for RetryTransport.
+const (
+	DefaultMaxTries    = 5
+	DefaultInitialWait = 1 * time.Second
+	DefaultMaxWait     = 30 * time.Second
+)
+
+// Retryable reports whether a request that produced resp and err should be
+// retried. Exactly one of resp or err is non-nil.
+type Retryable func(resp *http.Response, err error) bool
+
+// DefaultRetryable retries network errors, HTTP 5xx responses, and
+// responses whose JSON body carries Google's OVER_QUERY_LIMIT status.
+func DefaultRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	if resp.StatusCode >= 500 {
+		return true
+	}
+	return sniffOverQueryLimit(resp)
+}
+
+func sniffOverQueryLimit(resp *http.Response) bool {
+	if resp.Body == nil {
+		return false
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	var sniff struct {
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(body, &sniff); err != nil {
+		return false
+	}
+	return sniff.Status == "OVER_QUERY_LIMIT"
+}
+
+// RetryTransport is an http.RoundTripper that retries requests that fail
+// with a transient network error, a 5xx response, or a Google Maps
+// OVER_QUERY_LIMIT status, backing off exponentially with jitter between
+// attempts.
+type RetryTransport struct {
+	// Base is the underlying RoundTripper used to make requests. It
+	// defaults to http.DefaultTransport.
+	Base http.RoundTripper
+
+	// MaxTries is the maximum number of attempts, including the first.
+	// It defaults to DefaultMaxTries.
+	MaxTries int
+
+	// InitialWait is the delay before the first retry. It defaults to
+	// DefaultInitialWait and doubles (plus jitter) after every attempt.
+	InitialWait time.Duration
+
+	// MaxWait caps the delay between retries. It defaults to
+	// DefaultMaxWait.
+	MaxWait time.Duration
+
+	// Retryable decides whether a given response/error should be
+	// retried. It defaults to DefaultRetryable.
+	Retryable Retryable
+
+	// sleep is overridden in tests to avoid real delays.
+	sleep func(time.Duration)
+}
+
+// NewRetryTransport wraps base with exponential-backoff retry behavior
+// using the package defaults. The returned transport can be customized by
+// setting its exported fields before use.
+func NewRetryTransport(base http.RoundTripper) *RetryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RetryTransport{
+		Base:        base,
+		MaxTries:    DefaultMaxTries,
+		InitialWait: DefaultInitialWait,
+		MaxWait:     DefaultMaxWait,
+		Retryable:   DefaultRetryable,
+		sleep:       time.Sleep,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	maxTries := t.MaxTries
+	if maxTries <= 0 {
+		maxTries = DefaultMaxTries
+	}
+	retryable := t.Retryable
+	if retryable == nil {
+		retryable = DefaultRetryable
+	}
+	wait := t.InitialWait
+	if wait <= 0 {
+		wait = DefaultInitialWait
+	}
+	maxWait := t.MaxWait
+	if maxWait <= 0 {
+		maxWait = DefaultMaxWait
+	}
+	sleep := t.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	var resp *http.Response
+	var err error
+	for try := 0; try < maxTries; try++ {
+		resp, err = t.Base.RoundTrip(req)
+
+		if try == maxTries-1 || !retryable(resp, err) {
+			return resp, err
+		}
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+
+		sleep(wait)
+		wait = backoffWait(wait, maxWait)
+	}
+	return resp, err
+}
+
+// backoffWait doubles wait, adds up to ±500ms of jitter, and caps the
+// result at maxWait. It is shared by RetryTransport and BatchGeocode so
+// that both back off the same way when Google asks us to slow down.
+func backoffWait(wait, maxWait time.Duration) time.Duration {
+	jitter := time.Duration(rand.Intn(1000)-500) * time.Millisecond
+	wait = wait*2 + jitter
+	if wait > maxWait {
+		wait = maxWait
+	} else if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+// RetryOption configures a RetryTransport built by NewContextWithRetry.
+type RetryOption func(*RetryTransport)
+
+// WithMaxTries overrides RetryTransport.MaxTries.
+func WithMaxTries(maxTries int) RetryOption {
+	return func(t *RetryTransport) { t.MaxTries = maxTries }
+}
+
+// WithInitialWait overrides RetryTransport.InitialWait.
+func WithInitialWait(wait time.Duration) RetryOption {
+	return func(t *RetryTransport) { t.InitialWait = wait }
+}
+
+// WithMaxWait overrides RetryTransport.MaxWait.
+func WithMaxWait(wait time.Duration) RetryOption {
+	return func(t *RetryTransport) { t.MaxWait = wait }
+}
+
+// WithRetryable overrides RetryTransport.Retryable.
+func WithRetryable(r Retryable) RetryOption {
+	return func(t *RetryTransport) { t.Retryable = r }
+}
+
+// NewContextWithRetry builds a Context whose requests are retried on
+// transient 5xx and network failures using exponential backoff with
+// jitter. client may be nil, in which case a new client is created; its
+// existing Transport (if any) becomes the base for the retry transport.
+func NewContextWithRetry(apiKey string, client *http.Client, opts ...RetryOption) *Context {
+	if client == nil {
+		client = &http.Client{}
+	}
+
+	rt := NewRetryTransport(client.Transport)
+	for _, opt := range opts {
+		opt(rt)
+	}
+
+	retryClient := &http.Client{
+		Transport:     rt,
+		CheckRedirect: client.CheckRedirect,
+		Jar:           client.Jar,
+		Timeout:       client.Timeout,
+	}
+	return NewContext(apiKey, retryClient)
+}
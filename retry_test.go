@@ -0,0 +1,130 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransportRetriesOn5xxThenSucceeds(t *testing.T) {
+	var tries int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&tries, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status":"OK"}`))
+	}))
+	defer server.Close()
+
+	rt := NewRetryTransport(http.DefaultTransport)
+	rt.sleep = func(time.Duration) {}
+
+	client := &http.Client{Transport: rt}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if got, want := atomic.LoadInt32(&tries), int32(3); got != want {
+		t.Errorf("RoundTrip calls = %d, want %d", got, want)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("final status = %d, want 200", resp.StatusCode)
+	}
+}
+
+func TestRetryTransportGivesUpAfterMaxTries(t *testing.T) {
+	var tries int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tries, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	rt := NewRetryTransport(http.DefaultTransport)
+	rt.MaxTries = 3
+	rt.sleep = func(time.Duration) {}
+
+	client := &http.Client{Transport: rt}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if got, want := atomic.LoadInt32(&tries), int32(3); got != want {
+		t.Errorf("RoundTrip calls = %d, want %d", got, want)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("final status = %d, want 503", resp.StatusCode)
+	}
+}
+
+func TestRetryTransportRetriesOnOverQueryLimit(t *testing.T) {
+	var tries int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&tries, 1) < 2 {
+			w.Write([]byte(`{"status":"OVER_QUERY_LIMIT"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"OK"}`))
+	}))
+	defer server.Close()
+
+	rt := NewRetryTransport(http.DefaultTransport)
+	rt.sleep = func(time.Duration) {}
+
+	client := &http.Client{Transport: rt}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if got, want := atomic.LoadInt32(&tries), int32(2); got != want {
+		t.Errorf("RoundTrip calls = %d, want %d", got, want)
+	}
+}
+
+func TestRetryTransportDoesNotRetryOn4xx(t *testing.T) {
+	var tries int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&tries, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	rt := NewRetryTransport(http.DefaultTransport)
+	rt.sleep = func(time.Duration) {
+		t.Fatal("sleep should not be called when the response isn't retryable")
+	}
+
+	client := &http.Client{Transport: rt}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	resp.Body.Close()
+
+	if got, want := atomic.LoadInt32(&tries), int32(1); got != want {
+		t.Errorf("RoundTrip calls = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maps
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LatLng represents a point on the Earth's surface.
+type LatLng struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+func (l LatLng) String() string {
+	return strconv.FormatFloat(l.Lat, 'f', -1, 64) + "," + strconv.FormatFloat(l.Lng, 'f', -1, 64)
+}
+
+// LatLngBounds represents a rectangular viewport expressed as the
+// south-west and north-east corners.
+type LatLngBounds struct {
+	NorthEast LatLng `json:"northeast"`
+	SouthWest LatLng `json:"southwest"`
+}
+
+func (b LatLngBounds) String() string {
+	return fmt.Sprintf("%s|%s", b.SouthWest, b.NorthEast)
+}
+
+// AddressComponent is a piece of a formatted address, such as a street
+// number, route or locality.
+type AddressComponent struct {
+	LongName  string   `json:"long_name"`
+	ShortName string   `json:"short_name"`
+	Types     []string `json:"types"`
+}
+
+// Geometry describes the location and viewport of a geocoding result.
+type Geometry struct {
+	Location     LatLng        `json:"location"`
+	LocationType LocationType  `json:"location_type"`
+	Viewport     LatLngBounds  `json:"viewport"`
+	Bounds       *LatLngBounds `json:"bounds,omitempty"`
+}
+
+// LocationType qualifies how precisely a geocoding result's Geometry.Location
+// was determined.
+type LocationType string
+
+// Supported values for LocationType.
+const (
+	LocationTypeRooftop           = LocationType("ROOFTOP")
+	LocationTypeRangeInterpolated = LocationType("RANGE_INTERPOLATED")
+	LocationTypeGeometricCenter   = LocationType("GEOMETRIC_CENTER")
+	LocationTypeApproximate       = LocationType("APPROXIMATE")
+)
+
+func joinLocationTypes(types []LocationType) string {
+	s := make([]string, len(types))
+	for i, t := range types {
+		s[i] = string(t)
+	}
+	return strings.Join(s, "|")
+}